@@ -0,0 +1,231 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eddsa
+
+import (
+	"errors"
+	"hash"
+	"math/big"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+var (
+	errBatchLengthMismatch = errors.New("sigs, messages and pubKeys must have the same length")
+	errEmptyBatch          = errors.New("empty batch")
+	errMixedCurves         = errors.New("all signatures and public keys in a batch must be on the same curve")
+	// errAggregationInvariant signals that the combined batch equation
+	// failed even though every signature in the batch verifies
+	// individually. Since the batch equation is a public linear
+	// combination of those same per-signature equations, this can only
+	// happen if pippenger or batchScalars is miscomputing the
+	// aggregate — it must never be mistaken for "the batch is valid".
+	errAggregationInvariant = errors.New("eddsa: batch equation failed but every individual signature verified; this is an aggregation bug, not an invalid batch")
+)
+
+// zScalarBits is the bit size of the random batch-verification scalars z_i,
+// cf. the "small exponents" batch-verification trick (Bellare, Garay, Rabin).
+const zScalarBits = 128
+
+// VerifyBatch verifies a batch of eddsa signatures at once, using the
+// Pippenger bucket method to compute the combined multi-scalar
+// multiplication in less than O(N) scalar multiplications.
+//
+// It checks that
+//
+//	cofactor*(Σ z_i·s_i)·B == cofactor*(Σ z_i·R_i + Σ (z_i·H(R_i,A_i,M_i))·A_i)
+//
+// for random, publicly-derived 128 bit scalars z_i. A single mismatching
+// signature makes the whole equation fail with overwhelming probability,
+// in which case VerifyBatch falls back to verifying each signature one
+// by one and returns false at the first invalid one; the index of that
+// signature is not reported to the caller. If that
+// fallback finds every signature individually valid, the combined
+// equation must hold too; VerifyBatch returns errAggregationInvariant
+// rather than papering over what would be a real aggregation bug.
+func VerifyBatch(sigs []Signature, messages [][]byte, pubKeys []PublicKey, hFunc hash.Hash) (bool, error) {
+	n := len(sigs)
+	if n != len(messages) || n != len(pubKeys) {
+		return false, errBatchLengthMismatch
+	}
+	if n == 0 {
+		return false, errEmptyBatch
+	}
+
+	id := sigs[0].curve
+	c, err := getCurve(id)
+	if err != nil {
+		return false, err
+	}
+	for i := 0; i < n; i++ {
+		if sigs[i].curve != id || pubKeys[i].curve != id {
+			return false, errMixedCurves
+		}
+	}
+
+	zs, err := batchScalars(sigs, messages, pubKeys)
+	if err != nil {
+		return false, err
+	}
+
+	points := make([]Point, 0, 2*n)
+	scalars := make([]big.Int, 0, 2*n)
+
+	var sAccBig big.Int
+	for i := 0; i < n; i++ {
+		var s big.Int
+		s.SetBytes(sigs[i].S)
+		var zsProd big.Int
+		zsProd.Mul(&s, &zs[i])
+		sAccBig.Add(&sAccBig, &zsProd)
+
+		hram, err := challenge(sigs[i].R, pubKeys[i].A, messages[i], hFunc)
+		if err != nil {
+			return false, err
+		}
+		var zh big.Int
+		zh.Mul(&zs[i], hram)
+
+		points = append(points, sigs[i].R, pubKeys[i].A)
+		scalars = append(scalars, zs[i], zh)
+	}
+	sAccBig.Mod(&sAccBig, c.Order())
+
+	lhs := c.NewPoint()
+	lhs.ScalarMul(c.Base(), &sAccBig)
+	lhs.ScalarMul(lhs, c.Cofactor())
+
+	rhs := pippenger(c, points, scalars)
+	rhs.ScalarMul(rhs, c.Cofactor())
+
+	if lhs.Equal(rhs) {
+		return true, nil
+	}
+
+	// the aggregate check failed: fall back to verifying one by one, to
+	// tell an invalid signature apart from an aggregation bug.
+	for i := 0; i < n; i++ {
+		ok, err := Verify(sigs[i], messages[i], pubKeys[i], hFunc)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	// every individual signature verified, yet the combined equation
+	// above failed: this is a bug in the aggregation, not a valid batch.
+	return false, errAggregationInvariant
+}
+
+// batchScalars derives the per-signature random scalars z_i used to
+// combine the batch verification equation. The scalars are generated
+// deterministically from the batch inputs (sigs, messages, pubKeys) via
+// blake2b, so verification is reproducible and does not depend on any
+// secret material.
+func batchScalars(sigs []Signature, messages [][]byte, pubKeys []PublicKey) ([]big.Int, error) {
+	n := len(sigs)
+	zs := make([]big.Int, n)
+	for i := 0; i < n; i++ {
+		sigBytes := sigs[i].Bytes()
+		pubBytes := pubKeys[i].Bytes()
+		buf := make([]byte, 0, len(sigBytes)+len(pubBytes)+len(messages[i]))
+		buf = append(buf, sigBytes...)
+		buf = append(buf, pubBytes...)
+		buf = append(buf, messages[i]...)
+		digest := blake2b.Sum256(buf)
+		zs[i].SetBytes(digest[:zScalarBits/8])
+	}
+	return zs, nil
+}
+
+// pippenger computes Σ scalars[i]·points[i] using a bucket-based
+// multi-scalar multiplication (Pippenger's algorithm), so the cost of
+// verifying a batch of N signatures grows sublinearly in N instead of
+// requiring 2N independent scalar multiplications.
+func pippenger(c Curve, points []Point, scalars []big.Int) Point {
+	result := c.NewPoint()
+	identity(result)
+
+	const w = 8 // bucket window size in bits, tuned for batch sizes up to a few thousand
+	maxBits := 0
+	for i := range scalars {
+		if bl := scalars[i].BitLen(); bl > maxBits {
+			maxBits = bl
+		}
+	}
+	if maxBits == 0 {
+		return result
+	}
+
+	numWindows := (maxBits + w - 1) / w
+	numBuckets := 1 << uint(w)
+
+	for win := numWindows - 1; win >= 0; win-- {
+		// result = result * 2^w
+		for i := 0; i < w; i++ {
+			result.Add(result, result)
+		}
+
+		buckets := make([]Point, numBuckets)
+		used := make([]bool, numBuckets)
+
+		for i := range points {
+			digit := windowDigit(&scalars[i], win, w)
+			if digit == 0 {
+				continue
+			}
+			if !used[digit] {
+				buckets[digit] = c.NewPoint()
+				buckets[digit].ScalarMul(points[i], big.NewInt(1)) // buckets[digit] = points[i]
+				used[digit] = true
+			} else {
+				buckets[digit].Add(buckets[digit], points[i])
+			}
+		}
+
+		// sum buckets: running sum trick, Σ_{k=1}^{B-1} k·buckets[k]
+		windowSum := c.NewPoint()
+		identity(windowSum)
+		acc := c.NewPoint()
+		identity(acc)
+		for k := numBuckets - 1; k >= 1; k-- {
+			if used[k] {
+				acc.Add(acc, buckets[k])
+			}
+			windowSum.Add(windowSum, acc)
+		}
+		result.Add(result, windowSum)
+	}
+
+	return result
+}
+
+// identity sets p to the identity element (0,1) of the twisted Edwards
+// curve, by multiplying p by the scalar 0.
+func identity(p Point) {
+	p.ScalarMul(p, big.NewInt(0))
+}
+
+// windowDigit extracts the w-bit digit of x at window index win (win=0 is
+// the least significant window).
+func windowDigit(x *big.Int, win, w int) int {
+	shift := uint(win * w)
+	var tmp big.Int
+	tmp.Rsh(x, shift)
+	mask := big.NewInt(1<<uint(w) - 1)
+	tmp.And(&tmp, mask)
+	return int(tmp.Int64())
+}
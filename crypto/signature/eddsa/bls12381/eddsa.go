@@ -0,0 +1,87 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eddsa
+
+import (
+	"hash"
+
+	"github.com/consensys/gnark/crypto/signature/eddsa"
+)
+
+// PublicKey eddsa signature object, instantiated on the Jubjub/BLS12-381 curve.
+type PublicKey = eddsa.PublicKey
+
+// Signature represents an eddsa signature, instantiated on the
+// Jubjub/BLS12-381 curve.
+type Signature = eddsa.Signature
+
+// PrivateKey private key of an eddsa instance, instantiated on the
+// Jubjub/BLS12-381 curve.
+type PrivateKey = eddsa.PrivateKey
+
+// New creates an instance of eddsa on the Jubjub/BLS12-381 curve.
+func New(seed [32]byte) (PublicKey, PrivateKey) {
+	pub, priv, _ := eddsa.NewOnCurve(eddsa.BLS12_381, seed)
+	return pub, priv
+}
+
+// Sign signs a message.
+func Sign(message []byte, priv PrivateKey, hFunc hash.Hash) (Signature, error) {
+	return eddsa.Sign(message, priv, hFunc)
+}
+
+// Verify verifies an eddsa signature.
+func Verify(sig Signature, message []byte, pub PublicKey, hFunc hash.Hash) (bool, error) {
+	return eddsa.Verify(sig, message, pub, hFunc)
+}
+
+// VerifyBatch verifies a batch of eddsa signatures at once, see
+// eddsa.VerifyBatch.
+func VerifyBatch(sigs []Signature, messages [][]byte, pubKeys []PublicKey, hFunc hash.Hash) (bool, error) {
+	return eddsa.VerifyBatch(sigs, messages, pubKeys, hFunc)
+}
+
+// SignDeterministic signs message deterministically, see
+// eddsa.SignDeterministic.
+func SignDeterministic(message []byte, priv PrivateKey, hFunc hash.Hash) (Signature, error) {
+	return eddsa.SignDeterministic(message, priv, hFunc)
+}
+
+// SignPrehash signs a pre-computed digest, see eddsa.SignPrehash.
+func SignPrehash(digest []byte, priv PrivateKey, context []byte, hFunc hash.Hash) (Signature, error) {
+	return eddsa.SignPrehash(digest, priv, context, hFunc)
+}
+
+// VerifyPrehash verifies a signature produced by SignPrehash, see
+// eddsa.VerifyPrehash.
+func VerifyPrehash(sig Signature, digest []byte, pub PublicKey, context []byte, hFunc hash.Hash) (bool, error) {
+	return eddsa.VerifyPrehash(sig, digest, pub, context, hFunc)
+}
+
+// LoadFromPEM reads a PublicKey from the PEM file named s.
+func LoadFromPEM(s string) (PublicKey, error) {
+	return eddsa.LoadFromPEM(s, eddsa.BLS12_381)
+}
+
+// LoadSignatureFromPEM reads a Signature from the PEM file named s.
+func LoadSignatureFromPEM(s string) (Signature, error) {
+	return eddsa.LoadSignatureFromPEM(s, eddsa.BLS12_381)
+}
+
+// LoadPrivateKeyFromPEM reads back a PrivateKey dumped with
+// PrivateKey.DumpToPEMEncrypted.
+func LoadPrivateKeyFromPEM(s string, passphrase []byte) (PrivateKey, error) {
+	return eddsa.LoadPrivateKeyFromPEM(s, eddsa.BLS12_381, passphrase)
+}
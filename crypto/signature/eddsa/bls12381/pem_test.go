@@ -0,0 +1,100 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eddsa
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPublicKeyPEMRoundTrip(t *testing.T) {
+	var seed [32]byte
+	copy(seed[:], []byte("eddsa"))
+	pubKey, _ := New(seed)
+
+	path := filepath.Join(t.TempDir(), "pub.pem")
+	if err := pubKey.DumpToPEM(path); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadFromPEM(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.A.Equal(pubKey.A) {
+		t.Fatal("LoadFromPEM(DumpToPEM(pub)) != pub")
+	}
+}
+
+func TestSignaturePEMRoundTrip(t *testing.T) {
+	var seed [32]byte
+	copy(seed[:], []byte("eddsa"))
+	_, privKey := New(seed)
+	hFunc := sha256.New()
+	sig, err := Sign([]byte("message"), privKey, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "sig.pem")
+	if err := sig.DumpToPEM(path); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadSignatureFromPEM(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.R.Equal(sig.R) || !bytes.Equal(got.S, sig.S) {
+		t.Fatal("LoadSignatureFromPEM(DumpToPEM(sig)) != sig")
+	}
+}
+
+func TestPrivateKeyPEMEncryptedRoundTrip(t *testing.T) {
+	var seed [32]byte
+	copy(seed[:], []byte("eddsa"))
+	_, privKey := New(seed)
+
+	path := filepath.Join(t.TempDir(), "priv.pem")
+	passphrase := []byte("correct horse battery staple")
+	if err := privKey.DumpToPEMEncrypted(path, passphrase); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadPrivateKeyFromPEM(path, passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Marshal(), privKey.Marshal()) {
+		t.Fatal("LoadPrivateKeyFromPEM(DumpToPEMEncrypted(priv)) != priv")
+	}
+
+	if _, err := LoadPrivateKeyFromPEM(path, []byte("wrong passphrase")); err == nil {
+		t.Fatal("LoadPrivateKeyFromPEM should fail with the wrong passphrase")
+	}
+}
+
+func TestLoadFromPEMWrongCurve(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wrong.pem")
+	if err := os.WriteFile(path, []byte("-----BEGIN ED JUBJUB BN256 PUBLIC KEY-----\nAA==\n-----END ED JUBJUB BN256 PUBLIC KEY-----\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFromPEM(path); err == nil {
+		t.Fatal("expected an error loading a PEM block produced for a different curve")
+	}
+}
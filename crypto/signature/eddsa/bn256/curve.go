@@ -0,0 +1,102 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eddsa instantiates the generic eddsa package for the Jubjub
+// twisted Edwards curve defined over the scalar field of BN256.
+package eddsa
+
+import (
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark/crypto/signature/eddsa"
+	"github.com/consensys/gurvy/bn256/twistededwards"
+)
+
+const frSize = 32
+
+// point wraps twistededwards.PointAffine so it satisfies eddsa.Point.
+type point struct {
+	p twistededwards.PointAffine
+}
+
+func asPoint(p eddsa.Point) *point { return p.(*point) }
+
+func (pt *point) ScalarMul(base eddsa.Point, scalar *big.Int) eddsa.Point {
+	pt.p.ScalarMul(&asPoint(base).p, scalar)
+	return pt
+}
+
+func (pt *point) Add(p1, p2 eddsa.Point) eddsa.Point {
+	pt.p.Add(&asPoint(p1).p, &asPoint(p2).p)
+	return pt
+}
+
+func (pt *point) IsOnCurve() bool {
+	return pt.p.IsOnCurve()
+}
+
+func (pt *point) Equal(other eddsa.Point) bool {
+	o := asPoint(other)
+	return pt.p.X.Equal(&o.p.X) && pt.p.Y.Equal(&o.p.Y)
+}
+
+func (pt *point) Bytes() []byte {
+	x := pt.p.X.Bytes()
+	y := pt.p.Y.Bytes()
+	res := make([]byte, 0, 2*frSize)
+	res = append(res, x[:]...)
+	res = append(res, y[:]...)
+	return res
+}
+
+func (pt *point) SetBytes(buf []byte) (int, error) {
+	if len(buf) < 2*frSize {
+		return 0, io.ErrShortBuffer
+	}
+	pt.p.X.SetBytes(buf[:frSize])
+	pt.p.Y.SetBytes(buf[frSize : 2*frSize])
+	return 2 * frSize, nil
+}
+
+// curveBN256 implements eddsa.Curve for the Jubjub curve over BN256's
+// scalar field.
+type curveBN256 struct{}
+
+func (curveBN256) ID() eddsa.ID { return eddsa.BN256 }
+
+func (curveBN256) Base() eddsa.Point {
+	c := twistededwards.GetEdwardsCurve()
+	return &point{p: c.Base}
+}
+
+func (curveBN256) Order() *big.Int {
+	c := twistededwards.GetEdwardsCurve()
+	return &c.Order
+}
+
+func (curveBN256) Cofactor() *big.Int {
+	c := twistededwards.GetEdwardsCurve()
+	var bCofactor big.Int
+	c.Cofactor.ToBigInt(&bCofactor)
+	return &bCofactor
+}
+
+func (curveBN256) FrSize() int { return frSize }
+
+func (curveBN256) NewPoint() eddsa.Point { return &point{} }
+
+func init() {
+	eddsa.Register(eddsa.BN256, curveBN256{})
+}
@@ -0,0 +1,38 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eddsa
+
+import (
+	"github.com/consensys/gnark/crypto/signature/eddsa"
+)
+
+// DeriveChild derives the child (at position index) of privKey, see
+// eddsa.DeriveChild.
+func DeriveChild(privKey PrivateKey, index uint32, chainCode [32]byte) (PrivateKey, [32]byte) {
+	return eddsa.DeriveChild(privKey, index, chainCode)
+}
+
+// DerivePublicChild derives the non-hardened child (at position index)
+// of pubKey, see eddsa.DerivePublicChild.
+func DerivePublicChild(pubKey PublicKey, index uint32, chainCode [32]byte) (PublicKey, [32]byte) {
+	return eddsa.DerivePublicChild(pubKey, index, chainCode)
+}
+
+// NewFromMnemonic derives a master key pair and chain code on the
+// Jubjub/BN256 curve from a BIP39 mnemonic and passphrase, see
+// eddsa.NewFromMnemonic.
+func NewFromMnemonic(mnemonic, passphrase string) (PublicKey, PrivateKey, [32]byte, error) {
+	return eddsa.NewFromMnemonic(eddsa.BN256, mnemonic, passphrase)
+}
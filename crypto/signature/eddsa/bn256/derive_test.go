@@ -0,0 +1,153 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eddsa
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark/crypto/signature/eddsa"
+)
+
+func TestDeriveChildMatchesPublicDerivation(t *testing.T) {
+	var seed [32]byte
+	copy(seed[:], []byte("eddsa"))
+	masterPub, masterPriv := New(seed)
+	var chainCode [32]byte
+	copy(chainCode[:], []byte("chaincode"))
+
+	const index = 0 // below eddsa.HardenedOffset: non-hardened
+
+	childPriv, childChainCode := DeriveChild(masterPriv, index, chainCode)
+	childPubFromPublic, publicChainCode := DerivePublicChild(masterPub, index, chainCode)
+
+	childPub := childPriv.Public()
+	if !childPub.A.Equal(childPubFromPublic.A) {
+		t.Fatal("DeriveChild and DerivePublicChild disagree on the non-hardened child public key")
+	}
+	if childChainCode != publicChainCode {
+		t.Fatal("DeriveChild and DerivePublicChild disagree on the non-hardened child chain code")
+	}
+}
+
+func TestDeriveChildDeterministic(t *testing.T) {
+	var seed [32]byte
+	copy(seed[:], []byte("eddsa"))
+	_, masterPriv := New(seed)
+	var chainCode [32]byte
+	copy(chainCode[:], []byte("chaincode"))
+
+	child1, cc1 := DeriveChild(masterPriv, 7, chainCode)
+	child2, cc2 := DeriveChild(masterPriv, 7, chainCode)
+
+	if !bytes.Equal(child1.Marshal(), child2.Marshal()) || cc1 != cc2 {
+		t.Fatal("DeriveChild should be deterministic for the same inputs")
+	}
+
+	child3, _ := DeriveChild(masterPriv, 8, chainCode)
+	if bytes.Equal(child1.Marshal(), child3.Marshal()) {
+		t.Fatal("different indices should derive different children")
+	}
+}
+
+func TestDeriveChildHardened(t *testing.T) {
+	var seed [32]byte
+	copy(seed[:], []byte("eddsa"))
+	masterPub, masterPriv := New(seed)
+	var chainCode [32]byte
+	copy(chainCode[:], []byte("chaincode"))
+
+	hardenedIndex := eddsa.HardenedOffset
+
+	child1, cc1 := DeriveChild(masterPriv, hardenedIndex, chainCode)
+	child2, cc2 := DeriveChild(masterPriv, hardenedIndex, chainCode)
+	if !bytes.Equal(child1.Marshal(), child2.Marshal()) || cc1 != cc2 {
+		t.Fatal("DeriveChild should be deterministic for the same inputs")
+	}
+
+	// a hardened child cannot be obtained from the parent public key
+	// alone: DerivePublicChild, which only ever computes the
+	// non-hardened tweak, must not agree with it.
+	childPubFromPublic, _ := DerivePublicChild(masterPub, hardenedIndex, chainCode)
+	if child1.Public().A.Equal(childPubFromPublic.A) {
+		t.Fatal("a hardened child should not match DerivePublicChild's non-hardened tweak")
+	}
+}
+
+func TestDerivePublicChild(t *testing.T) {
+	var seed [32]byte
+	copy(seed[:], []byte("eddsa"))
+	masterPub, _ := New(seed)
+	var chainCode [32]byte
+	copy(chainCode[:], []byte("chaincode"))
+
+	childPub1, cc1 := DerivePublicChild(masterPub, 3, chainCode)
+	childPub2, cc2 := DerivePublicChild(masterPub, 3, chainCode)
+
+	if !childPub1.A.Equal(childPub2.A) || cc1 != cc2 {
+		t.Fatal("DerivePublicChild should be deterministic for the same inputs")
+	}
+}
+
+func TestDeriveChildRoundTripsThroughMarshal(t *testing.T) {
+	var seed [32]byte
+	copy(seed[:], []byte("eddsa"))
+	_, masterPriv := New(seed)
+	var chainCode [32]byte
+	copy(chainCode[:], []byte("chaincode"))
+
+	childPriv, _ := DeriveChild(masterPriv, 1, chainCode)
+
+	marshaled := childPriv.Marshal()
+	var unmarshaled PrivateKey
+	if _, err := unmarshaled.SetBytes(marshaled, eddsa.BN256); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(unmarshaled.Marshal(), childPriv.Marshal()) {
+		t.Fatal("unmarshal(marshal(derived private key)) failed")
+	}
+}
+
+func TestNewFromMnemonic(t *testing.T) {
+	pub1, priv1, cc1, err := NewFromMnemonic(
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		"",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub2, priv2, cc2, err := NewFromMnemonic(
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		"",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !pub1.A.Equal(pub2.A) || !bytes.Equal(priv1.Marshal(), priv2.Marshal()) || cc1 != cc2 {
+		t.Fatal("NewFromMnemonic should be deterministic for the same mnemonic/passphrase")
+	}
+
+	pub3, _, _, err := NewFromMnemonic(
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		"different passphrase",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pub3.A.Equal(pub1.A) {
+		t.Fatal("different passphrases should derive different master keys")
+	}
+}
@@ -15,10 +15,12 @@
 package eddsa
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"testing"
 
 	"github.com/consensys/gnark/crypto/hash/mimc/bn256"
+	"github.com/consensys/gnark/crypto/signature/eddsa"
 	"github.com/consensys/gurvy/bn256/fr"
 )
 
@@ -45,38 +47,27 @@ func TestSerialization(t *testing.T) {
 	var unMarshalPrivKey PrivateKey
 	var unMarshalSignature Signature
 
-	unMarshalPubKey.Unmarshal(marshalPubKey)
-	unMarshalPrivKey.Unmarshal(marshalprivKey)
-	unMarshalSignature.Unmarshal(marshalSignature)
+	unMarshalPubKey.SetBytes(marshalPubKey, eddsa.BN256)
+	unMarshalPrivKey.SetBytes(marshalprivKey, eddsa.BN256)
+	unMarshalSignature.SetBytes(marshalSignature, eddsa.BN256)
 
 	// public key
-	if !unMarshalPubKey.A.Equal(&pubKey.A) {
+	if !unMarshalPubKey.A.Equal(pubKey.A) {
 		t.Fatal("unmarshal(marshal(pubkey)) failed")
 	}
 
 	// signature
-	if !unMarshalSignature.R.Equal(&signature.R) {
+	if !unMarshalSignature.R.Equal(signature.R) {
 		t.Fatal("unmarshal(marshal(signature.R)) failed")
 	}
-	for i := 0; i < frSize; i++ {
-		if unMarshalSignature.S[i] != signature.S[i] {
-			t.Fatal("unmarshal(marshal(signature.S)) failed")
-		}
+	if !bytes.Equal(unMarshalSignature.S, signature.S) {
+		t.Fatal("unmarshal(marshal(signature.S)) failed")
 	}
 
-	// private key
-	if !privKey.pubKey.A.Equal(&unMarshalPrivKey.pubKey.A) {
-		t.Fatal("unmarshal(marshal(privKey.pubkey)) failed")
-	}
-	for i := 0; i < 32; i++ {
-		if privKey.randSrc[i] != unMarshalPrivKey.randSrc[i] {
-			t.Fatal("unmarshal(marshal(privKey.randSrc)) failed")
-		}
-	}
-	for i := 0; i < frSize; i++ {
-		if privKey.scalar[i] != unMarshalPrivKey.scalar[i] {
-			t.Fatal("unmarshal(marshal(signature.scalar)) failed")
-		}
+	// private key: compare full marshaled form since the scalar and
+	// randSrc are not exported
+	if !bytes.Equal(unMarshalPrivKey.Marshal(), privKey.Marshal()) {
+		t.Fatal("unmarshal(marshal(privKey)) failed")
 	}
 
 }
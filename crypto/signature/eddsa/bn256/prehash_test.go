@@ -0,0 +1,113 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eddsa
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark/crypto/hash/mimc/bn256"
+	"github.com/consensys/gnark/crypto/signature/eddsa"
+)
+
+func TestSignDeterministic(t *testing.T) {
+	var seed [32]byte
+	copy(seed[:], []byte("eddsa"))
+
+	_, privKey := New(seed)
+	hFunc := bn256.NewMiMC("seed")
+
+	sig1, err := SignDeterministic([]byte("message"), privKey, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig2, err := SignDeterministic([]byte("message"), privKey, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !sig1.R.Equal(sig2.R) || !bytes.Equal(sig1.S, sig2.S) {
+		t.Fatal("SignDeterministic should be reproducible for the same inputs")
+	}
+
+	ok, err := Verify(sig1, []byte("message"), privKey.Public(), hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("SignDeterministic signature should verify with plain Verify")
+	}
+}
+
+func TestSignVerifyPrehash(t *testing.T) {
+	var seed [32]byte
+	copy(seed[:], []byte("eddsa"))
+
+	pubKey, privKey := New(seed)
+	hFunc := bn256.NewMiMC("seed")
+	digest := []byte("precomputed digest of a long message")
+	context := []byte("gnark/test")
+
+	sig, err := SignPrehash(digest, privKey, context, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := VerifyPrehash(sig, digest, pubKey, context, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("VerifyPrehash should accept a matching signature")
+	}
+
+	ok, err = VerifyPrehash(sig, digest, pubKey, []byte("other context"), hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("VerifyPrehash should reject a mismatching context")
+	}
+}
+
+func TestVerifyPrehashRejectsOffCurveR(t *testing.T) {
+	var seed [32]byte
+	copy(seed[:], []byte("eddsa"))
+
+	pubKey, privKey := New(seed)
+	hFunc := bn256.NewMiMC("seed")
+	digest := []byte("precomputed digest of a long message")
+	context := []byte("gnark/test")
+
+	sig, err := SignPrehash(digest, privKey, context, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// corrupt R (the first 2*frSize bytes) so it no longer lies on the
+	// curve, mimicking an attacker-supplied signature.
+	marshaled := sig.Marshal()
+	for i := range marshaled[:64] {
+		marshaled[i] = 0xFF
+	}
+	var tampered Signature
+	if _, err := tampered.SetBytes(marshaled, eddsa.BN256); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := VerifyPrehash(tampered, digest, pubKey, context, hFunc); err == nil {
+		t.Fatal("VerifyPrehash should reject a signature with an off-curve R")
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eddsa
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark/crypto/hash/mimc/bw6761"
+	bn256 "github.com/consensys/gnark/crypto/signature/eddsa/bn256"
+)
+
+func genBatch(n int) ([]Signature, [][]byte, []PublicKey) {
+	sigs := make([]Signature, n)
+	messages := make([][]byte, n)
+	pubKeys := make([]PublicKey, n)
+
+	hFunc := bw6761.NewMiMC("seed")
+
+	for i := 0; i < n; i++ {
+		var seed [32]byte
+		s := []byte(fmt.Sprintf("eddsa-%d", i))
+		copy(seed[:], s)
+
+		pubKey, privKey := New(seed)
+		message := []byte(fmt.Sprintf("message-%d", i))
+		sig, err := Sign(message, privKey, hFunc)
+		if err != nil {
+			panic(err)
+		}
+
+		sigs[i] = sig
+		messages[i] = message
+		pubKeys[i] = pubKey
+	}
+
+	return sigs, messages, pubKeys
+}
+
+func TestVerifyBatch(t *testing.T) {
+	hFunc := bw6761.NewMiMC("seed")
+	sigs, messages, pubKeys := genBatch(8)
+
+	ok, err := VerifyBatch(sigs, messages, pubKeys, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("VerifyBatch on a valid batch should return true")
+	}
+
+	// corrupt one signature
+	messages[3] = []byte("tampered message")
+	ok, err = VerifyBatch(sigs, messages, pubKeys, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("VerifyBatch with a tampered message should return false")
+	}
+}
+
+func TestVerifyBatchMixedCurves(t *testing.T) {
+	hFunc := bw6761.NewMiMC("seed")
+	sigs, messages, pubKeys := genBatch(4)
+
+	// pubKeys[0] (not pubKeys[1:]) is the one the mixed-curve guard must
+	// check, see the fix for the guard that only validated index 1..n-1.
+	var foreignSeed [32]byte
+	copy(foreignSeed[:], []byte("other-curve"))
+	foreignPub, _ := bn256.New(foreignSeed)
+	pubKeys[0] = foreignPub
+
+	if _, err := VerifyBatch(sigs, messages, pubKeys, hFunc); err == nil {
+		t.Fatal("expected an error when pubKeys[0] is on a different curve than the rest of the batch")
+	}
+}
+
+func TestVerifyBatchLengthMismatch(t *testing.T) {
+	hFunc := bw6761.NewMiMC("seed")
+	sigs, messages, pubKeys := genBatch(4)
+
+	_, err := VerifyBatch(sigs, messages[:3], pubKeys, hFunc)
+	if err == nil {
+		t.Fatal("expected an error on mismatched batch lengths")
+	}
+}
+
+func benchmarkVerifyBatch(b *testing.B, n int) {
+	hFunc := bw6761.NewMiMC("seed")
+	sigs, messages, pubKeys := genBatch(n)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		VerifyBatch(sigs, messages, pubKeys, hFunc)
+	}
+}
+
+func BenchmarkVerifyBatch1(b *testing.B)   { benchmarkVerifyBatch(b, 1) }
+func BenchmarkVerifyBatch8(b *testing.B)   { benchmarkVerifyBatch(b, 8) }
+func BenchmarkVerifyBatch64(b *testing.B)  { benchmarkVerifyBatch(b, 64) }
+func BenchmarkVerifyBatch512(b *testing.B) { benchmarkVerifyBatch(b, 512) }
+
+func benchmarkVerifySerial(b *testing.B, n int) {
+	hFunc := bw6761.NewMiMC("seed")
+	sigs, messages, pubKeys := genBatch(n)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < n; j++ {
+			Verify(sigs[j], messages[j], pubKeys[j], hFunc)
+		}
+	}
+}
+
+func BenchmarkVerifySerial1(b *testing.B)   { benchmarkVerifySerial(b, 1) }
+func BenchmarkVerifySerial8(b *testing.B)   { benchmarkVerifySerial(b, 8) }
+func BenchmarkVerifySerial64(b *testing.B)  { benchmarkVerifySerial(b, 64) }
+func BenchmarkVerifySerial512(b *testing.B) { benchmarkVerifySerial(b, 512) }
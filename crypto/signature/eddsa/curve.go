@@ -0,0 +1,122 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eddsa implements EdDSA signature over twisted Edwards curves
+// defined on the scalar field of a pairing-friendly curve, so a gnark
+// circuit built over that pairing-friendly curve can verify the
+// signature natively. The core of the scheme (key generation, signing,
+// verification, batch verification, PEM I/O, HD derivation) is written
+// once against the Curve/Point interfaces below; concrete curves are
+// provided by the eddsa/bn256, eddsa/bls12381 and eddsa/bw6761
+// subpackages, which register themselves on import.
+package eddsa
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ID identifies a twisted Edwards curve usable with this package.
+type ID uint8
+
+const (
+	// unset is the zero value of ID. A zero-value PublicKey, PrivateKey
+	// or Signature (e.g. a bls12381.PublicKey{} that was never produced
+	// by New/NewOnCurve/SetBytes) therefore carries an ID that getCurve
+	// rejects, instead of silently resolving to a real curve.
+	unset ID = iota
+	// BN256 is the Jubjub twisted Edwards curve defined over the scalar
+	// field of BN256, as used by the original eddsa/bn256 package.
+	BN256
+	// BLS12_381 is the Jubjub twisted Edwards curve defined over the
+	// scalar field of BLS12-381.
+	BLS12_381
+	// BW6_761 is the twisted Edwards curve defined over the scalar
+	// field of BW6-761.
+	BW6_761
+)
+
+// String returns the curve name used in PEM block types, e.g.
+// "ED JUBJUB BN256 PUBLIC KEY".
+func (id ID) String() string {
+	switch id {
+	case BN256:
+		return "JUBJUB BN256"
+	case BLS12_381:
+		return "JUBJUB BLS12-381"
+	case BW6_761:
+		return "BW6-761"
+	case unset:
+		return "UNSET"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Point is a point on a twisted Edwards curve. Implementations wrap a
+// curve-specific affine point type (e.g. gurvy's twistededwards.PointAffine)
+// so the eddsa core can be written once and reused for every curve.
+type Point interface {
+	// ScalarMul sets p to scalar*base and returns p.
+	ScalarMul(base Point, scalar *big.Int) Point
+	// Add sets p to p1+p2 and returns p.
+	Add(p1, p2 Point) Point
+	// IsOnCurve reports whether p is a valid point of the curve.
+	IsOnCurve() bool
+	// Equal reports whether p and other represent the same point.
+	Equal(other Point) bool
+	// Bytes returns the big-endian X||Y encoding of p, of length
+	// 2*Curve.FrSize().
+	Bytes() []byte
+	// SetBytes sets p from buf (as produced by Bytes) and returns the
+	// number of bytes read.
+	SetBytes(buf []byte) (int, error)
+}
+
+// Curve exposes the parameters of a twisted Edwards curve needed to
+// implement EdDSA generically.
+type Curve interface {
+	// ID returns the curve identifier.
+	ID() ID
+	// Base returns the base point (generator) of the curve.
+	Base() Point
+	// Order returns the order of the base point's subgroup.
+	Order() *big.Int
+	// Cofactor returns the cofactor of the curve.
+	Cofactor() *big.Int
+	// FrSize returns the size in bytes of a curve coordinate / scalar.
+	FrSize() int
+	// NewPoint returns a new, zero-value point of this curve, suitable
+	// as a receiver for SetBytes.
+	NewPoint() Point
+}
+
+var curves = map[ID]Curve{}
+
+// Register makes a Curve implementation available to NewOnCurve and the
+// PEM loaders under the given ID. Curve subpackages call this from an
+// init function.
+func Register(id ID, c Curve) {
+	curves[id] = c
+}
+
+func getCurve(id ID) (Curve, error) {
+	c, ok := curves[id]
+	if !ok {
+		return nil, errUnknownCurve
+	}
+	return c, nil
+}
+
+var errUnknownCurve = errors.New("eddsa: no Curve registered for this ID (missing blank import of a curve subpackage?)")
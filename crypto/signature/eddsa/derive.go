@@ -0,0 +1,212 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eddsa
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"math/big"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// tags distinguish the several HMAC-SHA512 derivations performed on the
+// same chain code, so deriving a scalar, a chain code and a nonce source
+// from one (chainCode, index) pair never reuses the same HMAC input.
+const (
+	tagScalar  = 0x00
+	tagRandSrc = 0x01
+	tagPublic  = 0x02
+)
+
+// masterSeedKey is the SLIP-0010 fixed HMAC key used to derive a master
+// key and chain code from a BIP39 seed.
+var masterSeedKey = []byte("ed25519 seed")
+
+// HardenedOffset is the BIP32 convention marking hardened child indices:
+// index values below it derive non-hardened children (DeriveChild and
+// DerivePublicChild agree on the result), values at or above it derive
+// hardened children (only derivable from the parent private key, not
+// from the parent public key).
+const HardenedOffset = uint32(1) << 31
+
+func hmacSHA512(key []byte, parts ...[]byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	for _, p := range parts {
+		mac.Write(p)
+	}
+	return mac.Sum(nil)
+}
+
+func indexBytes(index uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], index)
+	return buf[:]
+}
+
+// DeriveChild derives the child (at position index) of privKey, following
+// the SLIP-0010 construction for Ed25519-like curves. Two derivation
+// modes are supported, selected by index:
+//
+//   - index < HardenedOffset: non-hardened derivation. The child scalar
+//     is parent_scalar + delta mod order, where delta is derived from
+//     the chain code and the *parent public key* (HMAC-SHA512 with
+//     key=chainCode and data=0x02||parent_pubkey||index). DerivePublicChild
+//     computes the very same delta from the public key alone, so the two
+//     functions always agree: DeriveChild(priv, i, cc).Public() ==
+//     DerivePublicChild(priv.Public(), i, cc). This lets a watch-only
+//     wallet derive child public keys that the key holder can
+//     independently derive the matching private key for.
+//   - index >= HardenedOffset: hardened derivation. The child scalar is a
+//     fresh HMAC-SHA512 digest of the parent scalar (data=0x00||
+//     parent_scalar||index), unrelated to the parent scalar by any
+//     algebraic relationship. There is no public-only equivalent: a
+//     hardened child can only ever be derived from the parent private key.
+//
+// In both modes the 64 byte HMAC output splits into a scalar (reduced
+// mod the curve order: the new scalar when hardened, delta when
+// non-hardened) and a new chain code, and the child's public key is
+// recomputed as A=scalar*B.
+func DeriveChild(privKey PrivateKey, index uint32, chainCode [32]byte) (PrivateKey, [32]byte) {
+	c, err := getCurve(privKey.curve)
+	if err != nil {
+		return PrivateKey{}, [32]byte{}
+	}
+
+	idx := indexBytes(index)
+
+	var scalarInt big.Int
+	var childChainCode [32]byte
+	if index < HardenedOffset {
+		delta, cc := derivePublicDelta(c, privKey.pubKey.Bytes(), idx, chainCode)
+		scalarInt.SetBytes(privKey.scalar)
+		scalarInt.Add(&scalarInt, delta).Mod(&scalarInt, c.Order())
+		childChainCode = cc
+	} else {
+		i := hmacSHA512(chainCode[:], []byte{tagScalar}, privKey.scalar, idx)
+		scalarInt.SetBytes(i[:32]).Mod(&scalarInt, c.Order())
+		copy(childChainCode[:], i[32:])
+	}
+
+	r := hmacSHA512(chainCode[:], []byte{tagRandSrc}, privKey.scalar, idx)
+
+	var child PrivateKey
+	child.curve = privKey.curve
+
+	frSize := c.FrSize()
+	child.scalar = make([]byte, frSize)
+	sb := scalarInt.Bytes()
+	copy(child.scalar[frSize-len(sb):], sb)
+	copy(child.randSrc[:], r[:32])
+
+	child.pubKey.curve = privKey.curve
+	child.pubKey.A = c.NewPoint()
+	child.pubKey.A.ScalarMul(c.Base(), &scalarInt)
+
+	return child, childChainCode
+}
+
+// derivePublicDelta derives the additive tweak (delta, childChainCode)
+// used by both DeriveChild's non-hardened branch and DerivePublicChild,
+// from the parent chain code, the parent public key's bytes and index
+// (HMAC-SHA512 with key=chainCode and data=0x02||parent_pubkey||index).
+// Deriving it identically in both places is what makes
+// DeriveChild(priv, i, cc).Public() == DerivePublicChild(priv.Public(), i, cc)
+// for non-hardened i.
+func derivePublicDelta(c Curve, pubKeyBytes, idx []byte, chainCode [32]byte) (*big.Int, [32]byte) {
+	i := hmacSHA512(chainCode[:], []byte{tagPublic}, pubKeyBytes, idx)
+
+	var deltaInt big.Int
+	deltaInt.SetBytes(i[:32]).Mod(&deltaInt, c.Order())
+
+	var childChainCode [32]byte
+	copy(childChainCode[:], i[32:])
+
+	return &deltaInt, childChainCode
+}
+
+// DerivePublicChild derives the non-hardened child (at position index) of
+// pubKey, given the parent chain code. Since only the public key is
+// available, the child is obtained by adding delta*B to the parent
+// public key, where delta is derivePublicDelta's tweak, computed from
+// the chain code and the parent public key alone. This lets a
+// watch-only wallet compute child public keys without ever seeing the
+// corresponding private scalars, and matches what DeriveChild computes
+// for the same (privKey.Public(), index, chainCode) when index is
+// non-hardened.
+func DerivePublicChild(pubKey PublicKey, index uint32, chainCode [32]byte) (PublicKey, [32]byte) {
+	c, err := getCurve(pubKey.curve)
+	if err != nil {
+		return PublicKey{}, [32]byte{}
+	}
+
+	idx := indexBytes(index)
+	deltaInt, childChainCode := derivePublicDelta(c, pubKey.Bytes(), idx, chainCode)
+
+	delta := c.NewPoint()
+	delta.ScalarMul(c.Base(), deltaInt)
+
+	var child PublicKey
+	child.curve = pubKey.curve
+	child.A = c.NewPoint()
+	child.A.Add(pubKey.A, delta)
+
+	return child, childChainCode
+}
+
+// NewFromMnemonic derives a master eddsa key pair and chain code on the
+// given curve from a BIP39 mnemonic and passphrase: a PBKDF2-HMAC-SHA512
+// pass over the mnemonic produces a 64 byte seed, which is then fed
+// through the SLIP-0010 master key derivation (HMAC-SHA512 keyed by the
+// fixed string "ed25519 seed") to obtain the master scalar and chain
+// code. Subsequent accounts can be derived with DeriveChild /
+// DerivePublicChild so wallets and account-abstraction circuits can
+// share deterministic identities.
+func NewFromMnemonic(id ID, mnemonic, passphrase string) (PublicKey, PrivateKey, [32]byte, error) {
+	c, err := getCurve(id)
+	if err != nil {
+		return PublicKey{}, PrivateKey{}, [32]byte{}, err
+	}
+
+	seed := pbkdf2.Key([]byte(mnemonic), []byte("mnemonic"+passphrase), 2048, 64, sha512.New)
+
+	i := hmacSHA512(masterSeedKey, seed)
+
+	var scalarInt big.Int
+	scalarInt.SetBytes(i[:32]).Mod(&scalarInt, c.Order())
+
+	var chainCode [32]byte
+	copy(chainCode[:], i[32:])
+
+	r := hmacSHA512(masterSeedKey, seed, []byte{tagRandSrc})
+
+	var priv PrivateKey
+	priv.curve = id
+	frSize := c.FrSize()
+	priv.scalar = make([]byte, frSize)
+	sb := scalarInt.Bytes()
+	copy(priv.scalar[frSize-len(sb):], sb)
+	copy(priv.randSrc[:], r[:32])
+
+	var pub PublicKey
+	pub.curve = id
+	pub.A = c.NewPoint()
+	pub.A.ScalarMul(c.Base(), &scalarInt)
+
+	priv.pubKey = pub
+
+	return pub, priv, chainCode, nil
+}
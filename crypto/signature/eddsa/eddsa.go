@@ -0,0 +1,385 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eddsa
+
+import (
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"math/big"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+var errNotOnCurve = errors.New("point not on curve")
+
+// PublicKey eddsa signature object
+// cf https://en.wikipedia.org/wiki/EdDSA for notation
+type PublicKey struct {
+	A     Point
+	curve ID
+}
+
+// SetBytes sets p from binary representation in buf.
+// buf represents a public key as x||y where x, y are
+// interpreted as big endian binary numbers corresponding
+// to the coordinates of a point on the twisted Edwards.
+// It returns the number of bytes read from the buffer.
+func (pk *PublicKey) SetBytes(buf []byte, id ID) (int, error) {
+	c, err := getCurve(id)
+	if err != nil {
+		return 0, err
+	}
+	pk.curve = id
+	pk.A = c.NewPoint()
+	n, err := pk.A.SetBytes(buf)
+	if err != nil {
+		return n, err
+	}
+	if !pk.A.IsOnCurve() {
+		return n, errNotOnCurve
+	}
+	return n, nil
+}
+
+// Unmarshal alias to SetBytes, using the curve this public key was
+// created on (via New/NewOnCurve or a previous SetBytes/Unmarshal call).
+func (pk *PublicKey) Unmarshal(buf []byte) error {
+	_, err := pk.SetBytes(buf, pk.curve)
+	return err
+}
+
+// Bytes returns the binary representation of pk
+// as x||y where x, y are the coordinates of the point
+// on the twisted Edwards as big endian integers.
+func (pk *PublicKey) Bytes() []byte {
+	return pk.A.Bytes()
+}
+
+// Marshal converts pk to binary, returning it as
+// a byte slice.
+func (pk *PublicKey) Marshal() []byte {
+	return pk.Bytes()
+}
+
+func keyType(id ID) string {
+	return fmt.Sprintf("ED %s PUBLIC KEY", id)
+}
+
+// DumpToPEM writes the content of pk to a PEM file
+// named s.
+func (pk *PublicKey) DumpToPEM(s string) error {
+	block := &pem.Block{
+		Type:  keyType(pk.curve),
+		Bytes: pk.Bytes(),
+	}
+	return ioutil.WriteFile(s, pem.EncodeToMemory(block), 0o600)
+}
+
+// Signature represents an eddsa signature
+// cf https://en.wikipedia.org/wiki/EdDSA for notation
+type Signature struct {
+	R     Point
+	S     []byte
+	curve ID
+}
+
+// SetBytes sets sig from a buffer in binary.
+// buf is read interpreted as x||y||s where
+// * x,y are the coordinates of a point on the twisted
+//	Edwards represented in big endian
+// * s=r+h(r,a,m) mod l, the Hasse bound guarantess that
+//	s is smaller than frSize (in particular it is supposed
+// 	s is NOT blinded)
+// It returns the number of bytes read from buf.
+func (sig *Signature) SetBytes(buf []byte, id ID) (int, error) {
+	c, err := getCurve(id)
+	if err != nil {
+		return 0, err
+	}
+	frSize := c.FrSize()
+	if len(buf) < 2*frSize+frSize {
+		return 0, io.ErrShortBuffer
+	}
+	sig.curve = id
+	sig.R = c.NewPoint()
+	n, err := sig.R.SetBytes(buf[:2*frSize])
+	if err != nil {
+		return n, err
+	}
+	sig.S = make([]byte, frSize)
+	copy(sig.S, buf[2*frSize:3*frSize])
+	n += frSize
+	return n, nil
+}
+
+// Unmarshal alias to SetBytes, using the curve this signature was
+// created on.
+func (sig *Signature) Unmarshal(buf []byte) error {
+	_, err := sig.SetBytes(buf, sig.curve)
+	return err
+}
+
+// Bytes returns the binary representation of sig
+// as a byte slice x||y||s where
+// * x, y are the coordinates of a point on the twisted
+//	Edwards represented in big endian
+// * s=r+h(r,a,m) mod l, the Hasse bound guarantess that
+//	s is smaller than frSize (in particular it is supposed
+// 	s is NOT blinded)
+func (sig *Signature) Bytes() []byte {
+	res := make([]byte, 0, len(sig.R.Bytes())+len(sig.S))
+	res = append(res, sig.R.Bytes()...)
+	res = append(res, sig.S...)
+	return res
+}
+
+// Marshal converts sig to binary, returning it as
+// a byte slice.
+func (sig *Signature) Marshal() []byte {
+	return sig.Bytes()
+}
+
+// PrivateKey private key of an eddsa instance
+type PrivateKey struct {
+	pubKey  PublicKey // copy of the associated public key
+	scalar  []byte    // secret scalar, in big Endian
+	randSrc [32]byte  // randomizer (non need to convert it when doing scalar mul --> random = H(randSrc,msg))
+	curve   ID
+}
+
+// Public returns the public key associated to privKey.
+func (privKey *PrivateKey) Public() PublicKey {
+	return privKey.pubKey
+}
+
+// SetBytes sets pk from buf, where buf is interpreted
+// as  publicKey||scalar||randSrc
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size frSize.
+// It returns the number byte read.
+func (privKey *PrivateKey) SetBytes(buf []byte, id ID) (int, error) {
+	c, err := getCurve(id)
+	if err != nil {
+		return 0, err
+	}
+	frSize := c.FrSize()
+	sizePrivateKey := 2*frSize + frSize + 32
+	if len(buf) < sizePrivateKey {
+		return 0, io.ErrShortBuffer
+	}
+	privKey.curve = id
+	n, err := privKey.pubKey.SetBytes(buf[:2*frSize], id)
+	if err != nil {
+		return n, err
+	}
+	privKey.scalar = make([]byte, frSize)
+	copy(privKey.scalar, buf[2*frSize:3*frSize])
+	copy(privKey.randSrc[:], buf[3*frSize:3*frSize+32])
+	n += frSize + 32
+	return n, nil
+}
+
+// Unmarshal alias to SetBytes, using the curve this private key was
+// created on.
+func (privKey *PrivateKey) Unmarshal(buf []byte) error {
+	_, err := privKey.SetBytes(buf, privKey.curve)
+	return err
+}
+
+// Bytes returns the binary representation of pk,
+// as byte slice publicKey||scalar||randSrc
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size frSize.
+func (privKey *PrivateKey) Bytes() []byte {
+	res := make([]byte, 0, len(privKey.pubKey.Bytes())+len(privKey.scalar)+32)
+	res = append(res, privKey.pubKey.Bytes()...)
+	res = append(res, privKey.scalar...)
+	res = append(res, privKey.randSrc[:]...)
+	return res
+}
+
+// Marshal converts privKey to binary, returning it as
+// a byte slice.
+func (privKey *PrivateKey) Marshal() []byte {
+	return privKey.Bytes()
+}
+
+// NewOnCurve creates an eddsa key pair on the twisted Edwards curve
+// registered under id (see Register). Use the curve subpackages'
+// New function (e.g. bn256.New) for the non-generic, curve-specific
+// entry point.
+func NewOnCurve(id ID, seed [32]byte) (PublicKey, PrivateKey, error) {
+	c, err := getCurve(id)
+	if err != nil {
+		return PublicKey{}, PrivateKey{}, err
+	}
+
+	var pub PublicKey
+	var priv PrivateKey
+	priv.curve = id
+	pub.curve = id
+
+	h := blake2b.Sum512(seed[:])
+	for i := 0; i < 32; i++ {
+		priv.randSrc[i] = h[i+32]
+	}
+
+	// prune the key
+	// https://tools.ietf.org/html/rfc8032#section-5.1.5, key generation
+	h[0] &= 0xF8
+	h[31] &= 0x7F
+	h[31] |= 0x40
+
+	// reverse first bytes because SetBytes interprets the stream as big
+	// endian but in eddsa specs s is the first 32 bytes in little endian
+	for i, j := 0, 32; i < j; i, j = i+1, j-1 {
+		h[i], h[j] = h[j], h[i]
+	}
+
+	frSize := c.FrSize()
+	priv.scalar = make([]byte, frSize)
+	// the pruned scalar is always 32 bytes; right-align it in frSize
+	copy(priv.scalar[frSize-32:], h[:32])
+
+	var bscalar big.Int
+	bscalar.SetBytes(priv.scalar)
+	pub.A = c.NewPoint()
+	pub.A.ScalarMul(c.Base(), &bscalar)
+
+	priv.pubKey = pub
+
+	return pub, priv, nil
+}
+
+// domSign separates Sign's nonce derivation from SignDeterministic's and
+// SignPrehash's, so the three signing modes can never derive the same
+// nonce R for the same underlying message bytes. Without this, signing
+// the same bytes once via Sign/SignDeterministic and once via SignPrehash
+// (digest=those bytes, context=nil) would reuse R under two different
+// challenges, leaking the private scalar (classic EdDSA nonce-reuse).
+var domSign = []byte("eddsa-sign")
+
+// challenge computes H(R,A,M).
+func challenge(R Point, A Point, message []byte, hFunc hash.Hash) (*big.Int, error) {
+	dataToHash := make([]byte, 0, len(R.Bytes())+len(A.Bytes())+len(message))
+	dataToHash = append(dataToHash, R.Bytes()...)
+	dataToHash = append(dataToHash, A.Bytes()...)
+	dataToHash = append(dataToHash, message...)
+	hFunc.Reset()
+	if _, err := hFunc.Write(dataToHash); err != nil {
+		return nil, err
+	}
+	var hramInt big.Int
+	hramInt.SetBytes(hFunc.Sum(nil))
+	return &hramInt, nil
+}
+
+// Sign signs a message.
+// cf https://en.wikipedia.org/wiki/EdDSA for the notations
+// Eddsa is supposed to be built upon Edwards (or twisted Edwards) curves having 256 bits group size and cofactor=4 or 8
+func Sign(message []byte, priv PrivateKey, hFunc hash.Hash) (Signature, error) {
+	c, err := getCurve(priv.curve)
+	if err != nil {
+		return Signature{}, err
+	}
+
+	var res Signature
+	res.curve = priv.curve
+
+	// randSrc = privKey.randSrc || domSign || msg, domSign keeping this
+	// nonce distinct from SignDeterministic's and SignPrehash's.
+	randSrc := make([]byte, 0, 32+len(domSign)+len(message))
+	randSrc = append(randSrc, priv.randSrc[:]...)
+	randSrc = append(randSrc, domSign...)
+	randSrc = append(randSrc, message...)
+
+	// randBytes = H(randSrc)
+	randBytes := blake2b.Sum512(randSrc)
+	var randScalarInt big.Int
+	randScalarInt.SetBytes(randBytes[:32])
+
+	// compute R = randScalar*Base
+	res.R = c.NewPoint()
+	res.R.ScalarMul(c.Base(), &randScalarInt)
+	if !res.R.IsOnCurve() {
+		return Signature{}, errNotOnCurve
+	}
+
+	hramInt, err := challenge(res.R, priv.pubKey.A, message, hFunc)
+	if err != nil {
+		return Signature{}, err
+	}
+
+	// Compute s = randScalarInt + H(R,A,M)*S
+	// going with big int to do ops mod curve order
+	var bscalar, bs big.Int
+	bscalar.SetBytes(priv.scalar)
+	bs.Mul(hramInt, &bscalar).
+		Add(&bs, &randScalarInt).
+		Mod(&bs, c.Order())
+	sb := bs.Bytes()
+	frSize := c.FrSize()
+	res.S = make([]byte, frSize)
+	copy(res.S[frSize-len(sb):], sb)
+
+	return res, nil
+}
+
+// Verify verifies an eddsa signature
+// cf https://en.wikipedia.org/wiki/EdDSA
+func Verify(sig Signature, message []byte, pub PublicKey, hFunc hash.Hash) (bool, error) {
+	c, err := getCurve(pub.curve)
+	if err != nil {
+		return false, err
+	}
+
+	// verify that pubKey and R are on the curve
+	if !pub.A.IsOnCurve() {
+		return false, errNotOnCurve
+	}
+	if !sig.R.IsOnCurve() {
+		return false, errNotOnCurve
+	}
+
+	hramInt, err := challenge(sig.R, pub.A, message, hFunc)
+	if err != nil {
+		return false, err
+	}
+
+	// lhs = cofactor*S*Base
+	var bs big.Int
+	bs.SetBytes(sig.S)
+	lhs := c.NewPoint()
+	lhs.ScalarMul(c.Base(), &bs)
+	lhs.ScalarMul(lhs, c.Cofactor())
+	if !lhs.IsOnCurve() {
+		return false, errNotOnCurve
+	}
+
+	// rhs = cofactor*(R + H(R,A,M)*A)
+	rhs := c.NewPoint()
+	rhs.ScalarMul(pub.A, hramInt)
+	rhs.Add(rhs, sig.R)
+	rhs.ScalarMul(rhs, c.Cofactor())
+	if !rhs.IsOnCurve() {
+		return false, errNotOnCurve
+	}
+
+	return lhs.Equal(rhs), nil
+}
@@ -0,0 +1,38 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eddsa
+
+import "testing"
+
+func TestNewOnCurveUnregistered(t *testing.T) {
+	// no curve subpackage is imported here, so ID 42 is not registered.
+	var seed [32]byte
+	if _, _, err := NewOnCurve(ID(42), seed); err != errUnknownCurve {
+		t.Fatalf("expected errUnknownCurve, got %v", err)
+	}
+}
+
+func TestIDString(t *testing.T) {
+	cases := map[ID]string{
+		BN256:     "JUBJUB BN256",
+		BLS12_381: "JUBJUB BLS12-381",
+		BW6_761:   "BW6-761",
+	}
+	for id, want := range cases {
+		if got := id.String(); got != want {
+			t.Fatalf("ID(%d).String() = %q, want %q", id, got, want)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eddsa
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+	saltSize     = 16
+)
+
+var (
+	errWrongCurve        = errors.New("PEM block was not produced for this curve")
+	errWrongPassphrase   = errors.New("wrong passphrase or corrupted PEM block")
+	errCorruptedEncoding = errors.New("corrupted encrypted private key encoding")
+)
+
+func privateKeyType(id ID) string {
+	return fmt.Sprintf("ED %s PRIVATE KEY", id)
+}
+
+func signatureType(id ID) string {
+	return fmt.Sprintf("ED %s SIGNATURE", id)
+}
+
+// LoadFromPEM reads a PublicKey on curve id from the PEM file named s,
+// rejecting the file if it was not produced for that curve.
+func LoadFromPEM(s string, id ID) (PublicKey, error) {
+	var pub PublicKey
+
+	data, err := ioutil.ReadFile(s)
+	if err != nil {
+		return pub, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return pub, errors.New("failed to decode PEM block")
+	}
+	if block.Type != keyType(id) {
+		return pub, errWrongCurve
+	}
+	if _, err := pub.SetBytes(block.Bytes, id); err != nil {
+		return pub, err
+	}
+	return pub, nil
+}
+
+// DumpToPEM writes the content of sig to a PEM file named s.
+func (sig *Signature) DumpToPEM(s string) error {
+	block := &pem.Block{
+		Type:  signatureType(sig.curve),
+		Bytes: sig.Bytes(),
+	}
+	return ioutil.WriteFile(s, pem.EncodeToMemory(block), 0o600)
+}
+
+// LoadSignatureFromPEM reads a Signature on curve id from the PEM file
+// named s.
+func LoadSignatureFromPEM(s string, id ID) (Signature, error) {
+	var sig Signature
+
+	data, err := ioutil.ReadFile(s)
+	if err != nil {
+		return sig, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return sig, errors.New("failed to decode PEM block")
+	}
+	if block.Type != signatureType(id) {
+		return sig, errWrongCurve
+	}
+	if _, err := sig.SetBytes(block.Bytes, id); err != nil {
+		return sig, err
+	}
+	return sig, nil
+}
+
+// DumpToPEMEncrypted writes privKey to the PEM file named s, with the key
+// material (scalar and randSrc) encrypted under passphrase using
+// scrypt (key derivation) + AES-GCM. The public key part is stored in
+// the clear since it is not sensitive; a wrong passphrase is detected
+// when AES-GCM authentication of the ciphertext fails on load.
+func (privKey *PrivateKey) DumpToPEMEncrypted(s string, passphrase []byte) error {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	key, err := scrypt.Key(passphrase, salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	// plaintext = scalar || randSrc, the public key is kept in clear
+	plaintext := make([]byte, 0, len(privKey.scalar)+32)
+	plaintext = append(plaintext, privKey.scalar...)
+	plaintext = append(plaintext, privKey.randSrc[:]...)
+	ciphertext := gcm.Seal(nil, nonce, plaintext, nil)
+
+	pub := privKey.pubKey.Bytes()
+
+	// encoded = salt || nonce || pub || ciphertext
+	encoded := make([]byte, 0, saltSize+len(nonce)+len(pub)+len(ciphertext))
+	encoded = append(encoded, salt...)
+	encoded = append(encoded, nonce...)
+	encoded = append(encoded, pub...)
+	encoded = append(encoded, ciphertext...)
+
+	pemBlock := &pem.Block{
+		Type:  privateKeyType(privKey.curve),
+		Bytes: encoded,
+	}
+	return ioutil.WriteFile(s, pem.EncodeToMemory(pemBlock), 0o600)
+}
+
+// LoadPrivateKeyFromPEM reads back a PrivateKey on curve id dumped with
+// DumpToPEMEncrypted, decrypting the key material with passphrase.
+func LoadPrivateKeyFromPEM(s string, id ID, passphrase []byte) (PrivateKey, error) {
+	var privKey PrivateKey
+
+	c, err := getCurve(id)
+	if err != nil {
+		return privKey, err
+	}
+
+	data, err := ioutil.ReadFile(s)
+	if err != nil {
+		return privKey, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return privKey, errors.New("failed to decode PEM block")
+	}
+	if block.Type != privateKeyType(id) {
+		return privKey, errWrongCurve
+	}
+	privKey.curve = id
+
+	encoded := block.Bytes
+	if len(encoded) < saltSize {
+		return privKey, errCorruptedEncoding
+	}
+	salt := encoded[:saltSize]
+	rest := encoded[saltSize:]
+
+	key, err := scrypt.Key(passphrase, salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return privKey, err
+	}
+	aesBlock, err := aes.NewCipher(key)
+	if err != nil {
+		return privKey, err
+	}
+	gcm, err := cipher.NewGCM(aesBlock)
+	if err != nil {
+		return privKey, err
+	}
+
+	sizePublicKey := 2 * c.FrSize()
+	nonceSize := gcm.NonceSize()
+	if len(rest) < nonceSize+sizePublicKey {
+		return privKey, errCorruptedEncoding
+	}
+	nonce := rest[:nonceSize]
+	pubBytes := rest[nonceSize : nonceSize+sizePublicKey]
+	ciphertext := rest[nonceSize+sizePublicKey:]
+
+	if _, err := privKey.pubKey.SetBytes(pubBytes, id); err != nil {
+		return privKey, err
+	}
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return privKey, errWrongPassphrase
+	}
+	if len(plaintext) != c.FrSize()+32 {
+		return privKey, errCorruptedEncoding
+	}
+	privKey.scalar = make([]byte, c.FrSize())
+	copy(privKey.scalar, plaintext[:c.FrSize()])
+	copy(privKey.randSrc[:], plaintext[c.FrSize():])
+
+	return privKey, nil
+}
@@ -0,0 +1,199 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eddsa
+
+import (
+	"hash"
+	"math/big"
+)
+
+// SignDeterministic signs message using hFunc for both the nonce
+// derivation and the challenge, instead of the BLAKE2b/hFunc split that
+// Sign uses. Deriving the nonce with the same hash the caller already
+// passes in (typically a circuit-friendly hash like MiMC) makes the
+// signature fully reproducible by a prover, which plain Sign cannot
+// guarantee since it always hashes the nonce with BLAKE2b.
+func SignDeterministic(message []byte, priv PrivateKey, hFunc hash.Hash) (Signature, error) {
+	c, err := getCurve(priv.curve)
+	if err != nil {
+		return Signature{}, err
+	}
+
+	var res Signature
+	res.curve = priv.curve
+
+	// randSrc = privKey.randSrc || domDeterministic || msg, hashed with
+	// hFunc so the nonce is derived with the same function used for the
+	// challenge below. domDeterministic keeps this nonce distinct from
+	// Sign's and SignPrehash's, see domSign.
+	randSrc := make([]byte, 0, 32+len(domDeterministic)+len(message))
+	randSrc = append(randSrc, priv.randSrc[:]...)
+	randSrc = append(randSrc, domDeterministic...)
+	randSrc = append(randSrc, message...)
+
+	hFunc.Reset()
+	if _, err := hFunc.Write(randSrc); err != nil {
+		return Signature{}, err
+	}
+	var randScalarInt big.Int
+	randScalarInt.SetBytes(hFunc.Sum(nil))
+
+	res.R = c.NewPoint()
+	res.R.ScalarMul(c.Base(), &randScalarInt)
+	if !res.R.IsOnCurve() {
+		return Signature{}, errNotOnCurve
+	}
+
+	hramInt, err := challenge(res.R, priv.pubKey.A, message, hFunc)
+	if err != nil {
+		return Signature{}, err
+	}
+
+	var bscalar, bs big.Int
+	bscalar.SetBytes(priv.scalar)
+	bs.Mul(hramInt, &bscalar).
+		Add(&bs, &randScalarInt).
+		Mod(&bs, c.Order())
+	sb := bs.Bytes()
+	frSize := c.FrSize()
+	res.S = make([]byte, frSize)
+	copy(res.S[frSize-len(sb):], sb)
+
+	return res, nil
+}
+
+// dom separates prehash ("ctx"-bearing) signatures from plain Sign/Verify
+// signatures, mirroring the Ed25519ph/Ed25519ctx domain separator from
+// RFC 8032 (section 5.1). It is folded into both the nonce (below) and
+// the challenge (challengePrehash), so SignPrehash can never agree on a
+// nonce R with Sign or SignDeterministic for the same bytes, see domSign.
+var dom = []byte("eddsa-prehash")
+
+// domDeterministic separates SignDeterministic's nonce derivation from
+// Sign's and SignPrehash's, see domSign.
+var domDeterministic = []byte("eddsa-sign-deterministic")
+
+// challengePrehash computes H(dom || R || A || ctx || M) where M is
+// already a digest (the caller is expected to have hashed the actual
+// message before calling SignPrehash/VerifyPrehash).
+func challengePrehash(R, A Point, digest, context []byte, hFunc hash.Hash) (*big.Int, error) {
+	dataToHash := make([]byte, 0, len(dom)+len(R.Bytes())+len(A.Bytes())+len(context)+len(digest))
+	dataToHash = append(dataToHash, dom...)
+	dataToHash = append(dataToHash, R.Bytes()...)
+	dataToHash = append(dataToHash, A.Bytes()...)
+	dataToHash = append(dataToHash, context...)
+	dataToHash = append(dataToHash, digest...)
+
+	hFunc.Reset()
+	if _, err := hFunc.Write(dataToHash); err != nil {
+		return nil, err
+	}
+	var hramInt big.Int
+	hramInt.SetBytes(hFunc.Sum(nil))
+	return &hramInt, nil
+}
+
+// SignPrehash signs a pre-computed digest of a (possibly long) message,
+// prepending a domain-separation context to the challenge input as
+// dom || R || A || ctx || M, following the Ed25519ph/Ed25519ctx split
+// from RFC 8032. This lets callers hash long messages outside the
+// circuit and only feed the digest to the in-circuit verifier.
+func SignPrehash(digest []byte, priv PrivateKey, context []byte, hFunc hash.Hash) (Signature, error) {
+	c, err := getCurve(priv.curve)
+	if err != nil {
+		return Signature{}, err
+	}
+
+	var res Signature
+	res.curve = priv.curve
+
+	// randSrc = privKey.randSrc || dom || ctx || digest; dom keeps this
+	// nonce distinct from Sign's and SignDeterministic's, see domSign.
+	randSrc := make([]byte, 0, 32+len(dom)+len(context)+len(digest))
+	randSrc = append(randSrc, priv.randSrc[:]...)
+	randSrc = append(randSrc, dom...)
+	randSrc = append(randSrc, context...)
+	randSrc = append(randSrc, digest...)
+
+	hFunc.Reset()
+	if _, err := hFunc.Write(randSrc); err != nil {
+		return Signature{}, err
+	}
+	var randScalarInt big.Int
+	randScalarInt.SetBytes(hFunc.Sum(nil))
+
+	res.R = c.NewPoint()
+	res.R.ScalarMul(c.Base(), &randScalarInt)
+	if !res.R.IsOnCurve() {
+		return Signature{}, errNotOnCurve
+	}
+
+	hramInt, err := challengePrehash(res.R, priv.pubKey.A, digest, context, hFunc)
+	if err != nil {
+		return Signature{}, err
+	}
+
+	var bscalar, bs big.Int
+	bscalar.SetBytes(priv.scalar)
+	bs.Mul(hramInt, &bscalar).
+		Add(&bs, &randScalarInt).
+		Mod(&bs, c.Order())
+	sb := bs.Bytes()
+	frSize := c.FrSize()
+	res.S = make([]byte, frSize)
+	copy(res.S[frSize-len(sb):], sb)
+
+	return res, nil
+}
+
+// VerifyPrehash verifies a signature produced by SignPrehash against the
+// same digest and context.
+func VerifyPrehash(sig Signature, digest []byte, pub PublicKey, context []byte, hFunc hash.Hash) (bool, error) {
+	c, err := getCurve(pub.curve)
+	if err != nil {
+		return false, err
+	}
+
+	if !pub.A.IsOnCurve() {
+		return false, errNotOnCurve
+	}
+	if !sig.R.IsOnCurve() {
+		return false, errNotOnCurve
+	}
+
+	hramInt, err := challengePrehash(sig.R, pub.A, digest, context, hFunc)
+	if err != nil {
+		return false, err
+	}
+
+	var bs big.Int
+	bs.SetBytes(sig.S)
+	lhs := c.NewPoint()
+	lhs.ScalarMul(c.Base(), &bs)
+	lhs.ScalarMul(lhs, c.Cofactor())
+	if !lhs.IsOnCurve() {
+		return false, errNotOnCurve
+	}
+
+	rhs := c.NewPoint()
+	rhs.ScalarMul(pub.A, hramInt)
+	rhs.Add(rhs, sig.R)
+	rhs.ScalarMul(rhs, c.Cofactor())
+	if !rhs.IsOnCurve() {
+		return false, errNotOnCurve
+	}
+
+	return lhs.Equal(rhs), nil
+}